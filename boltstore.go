@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+var jobsBucket = []byte("jobs")
+
+// boltStore is a Store backed by an embedded bbolt file, so job state
+// survives process restarts and crashes.
+type boltStore struct {
+	db    *bbolt.DB
+	limit int
+}
+
+// newBoltStore opens (creating if necessary) a bbolt database at path.
+func newBoltStore(path string, limit int) (*boltStore, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open bolt store %q: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(jobsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init bolt store %q: %w", path, err)
+	}
+
+	return &boltStore{db: db, limit: limit}, nil
+}
+
+func jobKey(id int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(id))
+	return key
+}
+
+func (s *boltStore) Save(entry DelayEntry) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(jobsBucket)
+
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if err := b.Put(jobKey(entry.ID), data); err != nil {
+			return err
+		}
+
+		return prune(b, s.limit)
+	})
+}
+
+func (s *boltStore) Get(id int) (DelayEntry, bool, error) {
+	var entry DelayEntry
+	found := false
+
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket(jobsBucket).Get(jobKey(id))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &entry)
+	})
+
+	return entry, found, err
+}
+
+func (s *boltStore) All() ([]DelayEntry, error) {
+	var all []DelayEntry
+
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(_, data []byte) error {
+			var entry DelayEntry
+			if err := json.Unmarshal(data, &entry); err != nil {
+				return err
+			}
+			all = append(all, entry)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortBySeq(all)
+	return all, nil
+}
+
+func (s *boltStore) Delete(id int) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(jobsBucket).Delete(jobKey(id))
+	})
+}
+
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
+
+// prune deletes the oldest *completed or cancelled* entries in b until at
+// most limit remain, evicting in creation order. In-progress and paused
+// jobs are never evicted: their runTimer goroutine, pause/cancel/extend
+// controls, and any callback all depend on the entry still being in the
+// store, so silently dropping it would orphan all of that. If there aren't
+// enough terminal entries to reach the bound, b is simply left over limit
+// until some do finish or get cancelled. Callers must be inside a writable
+// transaction.
+func prune(b *bbolt.Bucket, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+
+	var all []DelayEntry
+	if err := b.ForEach(func(_, data []byte) error {
+		var entry DelayEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return err
+		}
+		all = append(all, entry)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(all) <= limit {
+		return nil
+	}
+
+	sortBySeq(all)
+	excess := len(all) - limit
+	for _, entry := range all {
+		if excess <= 0 {
+			break
+		}
+		if !entry.IsCompleted && !entry.IsCancelled {
+			continue
+		}
+		if err := b.Delete(jobKey(entry.ID)); err != nil {
+			return err
+		}
+		excess--
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// apiError is the body of a structured API error response, in the
+// Prometheus/etcd v2 style: {"error": {"code": "...", "message": "..."}}.
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type apiErrorEnvelope struct {
+	Error apiError `json:"error"`
+}
+
+// writeErrorJSON writes a structured error envelope with the given HTTP
+// status, in place of the plain-text body http.Error would produce.
+func writeErrorJSON(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(apiErrorEnvelope{Error: apiError{Code: code, Message: message}})
+}
+
+// jobActionErrorCode maps a job-control error to the error code reported
+// alongside jobActionHTTPStatus's HTTP status.
+func jobActionErrorCode(err error) string {
+	switch {
+	case errors.Is(err, errJobNotFound):
+		return "job_not_found"
+	case errors.Is(err, errInvalidExtend):
+		return "invalid_extend"
+	case errors.Is(err, errJobFinished):
+		return "job_finished"
+	default:
+		return "internal_error"
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import "sync"
+
+// statusEvent is a snapshot of all jobs broadcast to SSE subscribers.
+type statusEvent struct {
+	Jobs []ApiStatusResponse
+}
+
+// statusHub fans out status snapshots to any number of SSE subscribers.
+// Subscribers that fall behind simply miss intermediate snapshots rather
+// than blocking the publisher.
+type statusHub struct {
+	mu          sync.Mutex
+	subscribers map[chan statusEvent]struct{}
+}
+
+func newStatusHub() *statusHub {
+	return &statusHub{subscribers: make(map[chan statusEvent]struct{})}
+}
+
+func (h *statusHub) subscribe() chan statusEvent {
+	ch := make(chan statusEvent, 1)
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *statusHub) unsubscribe(ch chan statusEvent) {
+	h.mu.Lock()
+	delete(h.subscribers, ch)
+	h.mu.Unlock()
+	close(ch)
+}
+
+// publish fans ev out to every current subscriber. A subscriber whose
+// channel is still full from the previous tick just drops this one.
+func (h *statusHub) publish(ev statusEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// statusBroadcast is the process-wide hub fed by runTimer completions and
+// the 1s ticker started in main, and drained by the SSE handlers.
+var statusBroadcast = newStatusHub()
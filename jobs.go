@@ -0,0 +1,377 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	errJobNotFound   = errors.New("job not found")
+	errJobFinished   = errors.New("job already completed or cancelled")
+	errInvalidExtend = errors.New("addSeconds must be positive")
+)
+
+// jobControl lets API handlers steer a running runTimer goroutine. Signals
+// are buffered by 1 so a handler never blocks on a goroutine that's busy
+// elsewhere in its select loop.
+type jobControl struct {
+	cancelCh chan struct{}
+	pauseCh  chan struct{}
+	resumeCh chan struct{}
+	extendCh chan struct{}
+}
+
+var (
+	jobsMu sync.Mutex
+	jobs   = make(map[int]*jobControl)
+)
+
+func registerJobControl(jobID int) *jobControl {
+	ctrl := &jobControl{
+		cancelCh: make(chan struct{}, 1),
+		pauseCh:  make(chan struct{}, 1),
+		resumeCh: make(chan struct{}, 1),
+		extendCh: make(chan struct{}, 1),
+	}
+	jobsMu.Lock()
+	jobs[jobID] = ctrl
+	jobsMu.Unlock()
+	return ctrl
+}
+
+func getJobControl(jobID int) (*jobControl, bool) {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	ctrl, ok := jobs[jobID]
+	return ctrl, ok
+}
+
+func removeJobControl(jobID int) {
+	jobsMu.Lock()
+	delete(jobs, jobID)
+	jobsMu.Unlock()
+}
+
+// entryLocksMu guards entryLocks, the registry of per-job mutexes handed
+// out by lockEntry.
+var (
+	entryLocksMu sync.Mutex
+	entryLocks   = make(map[int]*sync.Mutex)
+)
+
+// lockEntry serializes every read-modify-write against a job's DelayEntry
+// (store.Get, mutate, store.Save), across pause/resume/cancel/extend,
+// completeJob, and the webhook package's attempt/delivery bookkeeping.
+// Without it, two concurrent actions on the same job (e.g. an extend
+// racing a pause) can both Get the pre-mutation entry and the second Save
+// silently clobbers the first. Unlike jobControl, the lock outlives
+// runTimer so it still serializes webhook writes after a job completes.
+// lockEntry never removes an entry's mutex, trading a small, bounded
+// amount of long-lived memory per job ever created for a simple, always-
+// correct registry.
+func lockEntry(jobID int) func() {
+	entryLocksMu.Lock()
+	l, ok := entryLocks[jobID]
+	if !ok {
+		l = &sync.Mutex{}
+		entryLocks[jobID] = l
+	}
+	entryLocksMu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+// signal sends on ch without blocking if the goroutine reading it is busy;
+// the control loop always re-checks store state after waking, so a dropped
+// duplicate signal is harmless.
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// stopAndDrain stops t the way the time.Timer docs recommend before Reset,
+// so a pending tick from a racing fire doesn't leak into the next wait.
+func stopAndDrain(t *time.Timer) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+}
+
+// remainingFor computes how much delay a job has left, accounting for any
+// accumulated (and, if currently paused, ongoing) pause time.
+func remainingFor(jobID int) time.Duration {
+	entry, ok, err := store.Get(jobID)
+	if err != nil || !ok {
+		return 0
+	}
+
+	elapsedSecs, _, _ := getStatusDetails(entry)
+	remaining := time.Duration(entry.TotalDelaySecs-elapsedSecs) * time.Second
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining
+}
+
+// runTimer drives a single job from its current store state to completion
+// or cancellation, reacting to pause/resume/extend signals along the way.
+// It's re-entrant across restarts: resumePendingJobs calls it for any job
+// that was still in-progress or paused when the process last stopped.
+func runTimer(jobID int) {
+	ctrl := registerJobControl(jobID)
+	defer removeJobControl(jobID)
+
+	entry, ok, err := store.Get(jobID)
+	if err != nil || !ok {
+		return
+	}
+
+	timer := time.NewTimer(remainingFor(jobID))
+	defer timer.Stop()
+
+	paused := entry.PausedAt != nil
+	if paused {
+		stopAndDrain(timer)
+	}
+
+	for {
+		select {
+		case <-timer.C:
+			completeJob(jobID)
+			return
+
+		case <-ctrl.cancelCh:
+			return
+
+		case <-ctrl.pauseCh:
+			if paused {
+				continue
+			}
+			paused = true
+			stopAndDrain(timer)
+
+		case <-ctrl.resumeCh:
+			if !paused {
+				continue
+			}
+			paused = false
+			timer.Reset(remainingFor(jobID))
+
+		case <-ctrl.extendCh:
+			if paused {
+				continue // picked up by remainingFor once it resumes
+			}
+			stopAndDrain(timer)
+			timer.Reset(remainingFor(jobID))
+		}
+	}
+}
+
+// completeJob marks a job completed, records its completion drift, and
+// notifies metrics/SSE subscribers. Called when runTimer's timer fires.
+func completeJob(jobID int) {
+	unlock := lockEntry(jobID)
+	defer unlock()
+
+	entry, ok, err := store.Get(jobID)
+	if err != nil || !ok {
+		return
+	}
+
+	expectedCompletionTime := entry.DateTimeAdded.Add(
+		time.Duration(entry.TotalDelaySecs+entry.AccumulatedPauseSecs) * time.Second)
+	now := time.Now()
+	entry.IsCompleted = true
+	entry.CompletedTime = &now
+	if err := store.Save(entry); err != nil {
+		log.Printf("failed to persist completion for Job ID %d: %v", jobID, err)
+		return
+	}
+
+	log.Printf("Timer Job ID %d completed at %s.\n", jobID, now.Format(timeFormat))
+
+	jobCompletionDriftSeconds.Observe(now.Sub(expectedCompletionTime).Seconds())
+	refreshJobMetrics()
+	statusBroadcast.publish(statusEvent{Jobs: buildApiStatusList()})
+
+	if entry.CallbackURL != "" {
+		enqueueWebhook(webhookJob{
+			jobID: jobID,
+			url:   entry.CallbackURL,
+			payload: webhookPayload{
+				ID:                entry.ID,
+				Name:              entry.Name,
+				TotalDelaySeconds: entry.TotalDelaySecs,
+				CompletedTime:     now,
+				Status:            "completed",
+			},
+		})
+	}
+}
+
+// pauseJob freezes a job's elapsed-time accounting and wakes its runTimer
+// goroutine so the underlying timer stops ticking. Pausing an
+// already-paused job is a no-op.
+func pauseJob(jobID int) error {
+	unlock := lockEntry(jobID)
+	defer unlock()
+
+	entry, ok, err := store.Get(jobID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errJobNotFound
+	}
+	if entry.IsCompleted || entry.IsCancelled {
+		return errJobFinished
+	}
+	if entry.PausedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	entry.PausedAt = &now
+	if err := store.Save(entry); err != nil {
+		return err
+	}
+
+	if ctrl, ok := getJobControl(jobID); ok {
+		signal(ctrl.pauseCh)
+	}
+
+	refreshJobMetrics()
+	statusBroadcast.publish(statusEvent{Jobs: buildApiStatusList()})
+	return nil
+}
+
+// resumeJob ends a pause, folding the paused interval into
+// AccumulatedPauseSecs, and wakes runTimer to restart its timer for
+// whatever time is left. Resuming a non-paused job is a no-op.
+func resumeJob(jobID int) error {
+	unlock := lockEntry(jobID)
+	defer unlock()
+
+	entry, ok, err := store.Get(jobID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errJobNotFound
+	}
+	if entry.IsCompleted || entry.IsCancelled {
+		return errJobFinished
+	}
+	if entry.PausedAt == nil {
+		return nil
+	}
+
+	entry.AccumulatedPauseSecs += int(time.Since(*entry.PausedAt).Seconds())
+	entry.PausedAt = nil
+	if err := store.Save(entry); err != nil {
+		return err
+	}
+
+	if ctrl, ok := getJobControl(jobID); ok {
+		signal(ctrl.resumeCh)
+	}
+
+	refreshJobMetrics()
+	statusBroadcast.publish(statusEvent{Jobs: buildApiStatusList()})
+	return nil
+}
+
+// cancelJob stops a job short of completion. Cancelling an
+// already-cancelled job is a no-op.
+func cancelJob(jobID int) error {
+	unlock := lockEntry(jobID)
+	defer unlock()
+
+	entry, ok, err := store.Get(jobID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errJobNotFound
+	}
+	if entry.IsCompleted {
+		return errJobFinished
+	}
+	if entry.IsCancelled {
+		return nil
+	}
+
+	now := time.Now()
+	entry.IsCancelled = true
+	entry.CancelledTime = &now
+	if err := store.Save(entry); err != nil {
+		return err
+	}
+
+	if ctrl, ok := getJobControl(jobID); ok {
+		signal(ctrl.cancelCh)
+	}
+
+	log.Printf("Timer Job ID %d cancelled at %s.\n", jobID, now.Format(timeFormat))
+
+	refreshJobMetrics()
+	statusBroadcast.publish(statusEvent{Jobs: buildApiStatusList()})
+	return nil
+}
+
+// extendJob adds addSeconds to a job's total delay, in progress or paused.
+func extendJob(jobID int, addSeconds int) error {
+	if addSeconds <= 0 {
+		return errInvalidExtend
+	}
+
+	unlock := lockEntry(jobID)
+	defer unlock()
+
+	entry, ok, err := store.Get(jobID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errJobNotFound
+	}
+	if entry.IsCompleted || entry.IsCancelled {
+		return errJobFinished
+	}
+
+	entry.TotalDelaySecs += addSeconds
+	if err := store.Save(entry); err != nil {
+		return err
+	}
+
+	if ctrl, ok := getJobControl(jobID); ok {
+		signal(ctrl.extendCh)
+	}
+
+	statusBroadcast.publish(statusEvent{Jobs: buildApiStatusList()})
+	return nil
+}
+
+// jobActionHTTPStatus maps a job-control error to the HTTP status code the
+// API surface should report for it.
+func jobActionHTTPStatus(err error) int {
+	switch {
+	case errors.Is(err, errJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errInvalidExtend):
+		return http.StatusBadRequest
+	case errors.Is(err, errJobFinished):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -2,23 +2,37 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/gorilla/mux"
 )
 
 // Define a struct for a single history/active entry.
 type DelayEntry struct {
-	ID             int        `json:"id"`
-	Name           string     `json:"name"`
-	DateTimeAdded  time.Time  `json:"dateTimeAdded"`
-	TotalDelaySecs int        `json:"totalDelaySeconds"`
-	IsCompleted    bool       `json:"isCompleted"`
-	CompletedTime  *time.Time `json:"completedTime,omitempty"`
+	ID                   int        `json:"id"`
+	Seq                  int64      `json:"seq"`
+	Name                 string     `json:"name"`
+	DateTimeAdded        time.Time  `json:"dateTimeAdded"`
+	TotalDelaySecs       int        `json:"totalDelaySeconds"`
+	IsCompleted          bool       `json:"isCompleted"`
+	CompletedTime        *time.Time `json:"completedTime,omitempty"`
+	IsCancelled          bool       `json:"isCancelled"`
+	CancelledTime        *time.Time `json:"cancelledTime,omitempty"`
+	PausedAt             *time.Time `json:"pausedAt,omitempty"`
+	AccumulatedPauseSecs int        `json:"accumulatedPauseSeconds"`
+
+	CallbackURL           string `json:"callbackUrl,omitempty"`
+	WebhookAttempts       int    `json:"webhookAttempts,omitempty"`
+	WebhookLastStatusCode int    `json:"webhookLastStatusCode,omitempty"`
+	WebhookDelivered      bool   `json:"webhookDelivered,omitempty"`
 }
 
 // Struct specifically for API responses that include dynamic status fields
@@ -31,17 +45,59 @@ type ApiStatusResponse struct {
 	ElapsedTimeSecs   int        `json:"elapsedTimeSeconds"`
 	RemainingTimeSecs int        `json:"remainingTimeSeconds"`
 	CompletedTime     *time.Time `json:"completedTime,omitempty"`
+	CancelledTime     *time.Time `json:"cancelledTime,omitempty"`
+
+	CallbackURL           string `json:"callbackUrl,omitempty"`
+	WebhookAttempts       int    `json:"webhookAttempts,omitempty"`
+	WebhookLastStatusCode int    `json:"webhookLastStatusCode,omitempty"`
+	WebhookDelivered      bool   `json:"webhookDelivered,omitempty"`
 }
 
 // Global variables to manage the shared state.
 var (
-	mu           sync.Mutex
-	history      = make(map[int]DelayEntry)
-	historyOrder []int // keep IDs in insertion order
-	nextEntryID  = 1
-	maxHistory   = 10
+	store Store
+
+	idMu        sync.Mutex
+	nextEntryID       = 1
+	nextSeq     int64 = 1
+
+	maxHistory = 10
 )
 
+// allocateID reserves the next job ID and creation sequence. The sequence
+// is stored on the entry itself so ordering survives a restart, independent
+// of how the store bounds or evicts entries.
+func allocateID() (id int, seq int64) {
+	idMu.Lock()
+	defer idMu.Unlock()
+	id, seq = nextEntryID, nextSeq
+	nextEntryID++
+	nextSeq++
+	return id, seq
+}
+
+// createJob allocates a new job ID, persists the entry, and starts its
+// runTimer goroutine. Shared by the HTML "/start" form, "/api/start", and
+// "/api/countdowns".
+func createJob(name string, delaySecs int, callbackURL string) (DelayEntry, error) {
+	jobID, seq := allocateID()
+	newEntry := DelayEntry{
+		ID:             jobID,
+		Seq:            seq,
+		Name:           name,
+		DateTimeAdded:  time.Now(),
+		TotalDelaySecs: delaySecs,
+		CallbackURL:    callbackURL,
+	}
+	if err := store.Save(newEntry); err != nil {
+		return DelayEntry{}, err
+	}
+
+	log.Printf("Timer Job ID %d created. Delay: %d seconds, Name: %q\n", jobID, delaySecs, name)
+	go runTimer(jobID)
+	return newEntry, nil
+}
+
 const timeFormat = "2006-01-02 15:04:05 MST"
 
 // --- Helper Functions (Navigation & Status Calculation) ---
@@ -58,6 +114,8 @@ const navBarHTML = `
     tr:nth-child(even) { background-color: #f2f2f2; }
     .status-complete { color: green; font-weight: bold; }
     .status-progress { color: orange; font-weight: bold; }
+    .status-paused { color: #b8860b; font-weight: bold; }
+    .status-cancelled { color: #999; font-weight: bold; text-decoration: line-through; }
 
     /* Styles for form alignment */
     .form-group {
@@ -82,6 +140,8 @@ const navBarHTML = `
     <a href="/status">View Statuses (/status)</a>
     | API:
     <a href="/api/status">All Statuses</a>
+    <a href="/api/status/stream">Live Status (SSE)</a>
+    <a href="/metrics">Metrics</a>
 </nav>
 <div class="content">
 `
@@ -93,53 +153,66 @@ func writeResponseWithNav(w http.ResponseWriter, content string) {
 	fmt.Fprint(w, navBarEndHTML)
 }
 
+// pausedSecsFor returns how many seconds of pause time an entry has
+// accumulated, including any ongoing pause, so callers can push its
+// expected completion time out by the same amount.
+func pausedSecsFor(entry DelayEntry) int {
+	pausedSecs := entry.AccumulatedPauseSecs
+	if entry.PausedAt != nil {
+		pausedSecs += int(time.Since(*entry.PausedAt).Seconds())
+	}
+	return pausedSecs
+}
+
+// expectedCompletionTimeFor returns when an entry is due to complete,
+// accounting for any time it has spent paused.
+func expectedCompletionTimeFor(entry DelayEntry) time.Time {
+	return entry.DateTimeAdded.Add(
+		time.Duration(entry.TotalDelaySecs+pausedSecsFor(entry)) * time.Second)
+}
+
+// getStatusDetails derives a job's elapsed time and status from its stored
+// fields. Paused intervals (past, via AccumulatedPauseSecs, and ongoing, via
+// PausedAt) are excluded from elapsed time so progress freezes while paused.
 func getStatusDetails(entry DelayEntry) (elapsedSecs int, currentStatus, statusClass string) {
-	expectedCompletionTime := entry.DateTimeAdded.Add(time.Duration(entry.TotalDelaySecs) * time.Second)
-	elapsedDuration := time.Since(entry.DateTimeAdded)
-	elapsedSecs = int(elapsedDuration.Seconds())
-	currentStatus = "in-progress"
-	statusClass = "status-progress"
+	pausedSecs := pausedSecsFor(entry)
+
+	elapsedSecs = int(time.Since(entry.DateTimeAdded).Seconds()) - pausedSecs
+	if elapsedSecs < 0 {
+		elapsedSecs = 0
+	}
 
-	if entry.IsCompleted || time.Now().After(expectedCompletionTime) {
+	expectedCompletionTime := expectedCompletionTimeFor(entry)
+
+	switch {
+	case entry.IsCancelled:
+		currentStatus = "cancelled"
+		statusClass = "status-cancelled"
+	case entry.IsCompleted || (entry.PausedAt == nil && time.Now().After(expectedCompletionTime)):
 		currentStatus = "completed"
 		statusClass = "status-complete"
 		elapsedSecs = entry.TotalDelaySecs
+	case entry.PausedAt != nil:
+		currentStatus = "paused"
+		statusClass = "status-paused"
+	default:
+		currentStatus = "in-progress"
+		statusClass = "status-progress"
 	}
-	return elapsedSecs, currentStatus, statusClass
-}
 
-// keepHistoryBounded enforces maxHistory by deleting the oldest entries.
-func keepHistoryBounded() {
-	if len(historyOrder) <= maxHistory {
-		return
-	}
-	for len(historyOrder) > maxHistory {
-		oldestID := historyOrder[0]
-		historyOrder = historyOrder[1:]
-		delete(history, oldestID)
-	}
+	return elapsedSecs, currentStatus, statusClass
 }
 
-// parseJobIDFromPath extracts the trailing numeric ID from a path like "/status/123".
-func parseJobIDFromPath(path string, prefix string) (int, error) {
-	path = strings.TrimSuffix(path, "/")
-	parts := strings.Split(path, "/")
-	// Expect ["", prefix, "{id}"]
-	if len(parts) != 3 || parts[1] != prefix {
-		return 0, fmt.Errorf("invalid path")
-	}
-	return strconv.Atoi(parts[2])
+// idVar extracts the numeric {id} path variable set by a mux route pattern
+// like "/api/status/{id:[0-9]+}".
+func idVar(r *http.Request) (int, error) {
+	return strconv.Atoi(mux.Vars(r)["id"])
 }
 
-// parseAPIJobIDFromPath extracts ID from a path like "/api/status/123".
-func parseAPIJobIDFromPath(path string) (int, error) {
-	path = strings.TrimSuffix(path, "/")
-	parts := strings.Split(path, "/")
-	// Expect ["", "api", "status", "{id}"]
-	if len(parts) != 4 || parts[1] != "api" || parts[2] != "status" {
-		return 0, fmt.Errorf("invalid path")
-	}
-	return strconv.Atoi(parts[3])
+// wantsJSON reports whether a request's Accept header asks for JSON rather
+// than the default HTML view, for routes that serve both.
+func wantsJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
 }
 
 // --- Standard HTML Handlers ---
@@ -147,13 +220,16 @@ func parseAPIJobIDFromPath(path string) (int, error) {
 func indexHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
-	mu.Lock()
-	defer mu.Unlock()
+	entries, err := store.All()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	content := "<h1>Countdown As A Service</h1>"
-	content += "<h2>Countdown History (Last 10)</h2>"
+	content += fmt.Sprintf("<h2>Countdown History (Last %d)</h2>", maxHistory)
 
-	if len(historyOrder) == 0 {
+	if len(entries) == 0 {
 		content += "<p>No delays recorded yet.</p>"
 		writeResponseWithNav(w, content)
 		return
@@ -171,19 +247,10 @@ func indexHandler(w http.ResponseWriter, r *http.Request) {
 			<th>Current Status</th>
 		</tr>`
 
-	// Use the last up-to-maxHistory entries from historyOrder
-	start := 0
-	if len(historyOrder) > maxHistory {
-		start = len(historyOrder) - maxHistory
-	}
-	for _, id := range historyOrder[start:] {
-		entry, ok := history[id]
-		if !ok {
-			continue
-		}
-
+	// store.All() already returns at most maxHistory entries, oldest first.
+	for _, entry := range entries {
 		elapsedSecs, currentStatus, statusClass := getStatusDetails(entry)
-		expectedCompletionTime := entry.DateTimeAdded.Add(time.Duration(entry.TotalDelaySecs) * time.Second)
+		expectedCompletionTime := expectedCompletionTimeFor(entry)
 
 		addedTimeStr := entry.DateTimeAdded.Format(timeFormat)
 		completeTimeStr := expectedCompletionTime.Format(timeFormat)
@@ -230,6 +297,10 @@ func startHandler(w http.ResponseWriter, r *http.Request) {
             <div class="form-group">
                 <label for="delay">Countdown Delay (in secs):</label>
 			    <input type="number" id="delay" name="delay" required min="1">
+            </div>
+            <div class="form-group">
+                <label for="callbackUrl">Webhook Callback URL (optional):</label>
+			    <input type="url" id="callbackUrl" name="callbackUrl">
             </div>
 			<button type="submit">Activate Rule</button>
 		</form>`
@@ -244,26 +315,10 @@ func startHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		mu.Lock()
-		newEntry := DelayEntry{
-			ID:             nextEntryID,
-			Name:           jobName,
-			DateTimeAdded:  time.Now(),
-			TotalDelaySecs: delay,
-			IsCompleted:    false,
-			CompletedTime:  nil,
+		if _, err := createJob(jobName, delay, r.FormValue("callbackUrl")); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
-		history[nextEntryID] = newEntry
-		historyOrder = append(historyOrder, nextEntryID)
-		keepHistoryBounded()
-
-		jobID := nextEntryID
-		nextEntryID++
-		mu.Unlock()
-
-		log.Printf("Timer Job ID %d created. Delay: %d seconds, Name: %q\n", jobID, delay, jobName)
-
-		go runTimer(jobID, delay)
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 
 	default:
@@ -271,34 +326,69 @@ func startHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// statusIndexHandler serves GET /status, content-negotiating between the
+// HTML table view and a JSON list of the same active jobs for
+// "Accept: application/json" clients.
 func statusIndexHandler(w http.ResponseWriter, r *http.Request) {
+	if wantsJSON(r) {
+		statusIndexJSON(w, r)
+		return
+	}
+	statusIndexHTML(w, r)
+}
+
+// statusIndexJSON is the JSON counterpart of statusIndexHTML: the same
+// still-active jobs, as ApiStatusResponse objects.
+func statusIndexJSON(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	active := make([]ApiStatusResponse, 0)
+	for _, entry := range buildApiStatusList() {
+		if entry.Status == "completed" || entry.Status == "cancelled" {
+			continue
+		}
+		active = append(active, entry)
+	}
+
+	if err := json.NewEncoder(w).Encode(active); err != nil {
+		writeErrorJSON(w, http.StatusInternalServerError, "internal_error", err.Error())
+	}
+}
+
+func statusIndexHTML(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
-	mu.Lock()
-	defer mu.Unlock()
+	entries, err := store.All()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	content := "<h1>Active Countdown Status</h1>"
 	activeCount := 0
 
-	for _, entry := range history {
-		if !entry.IsCompleted {
-			activeCount++
-			elapsedDuration := time.Since(entry.DateTimeAdded)
-			remaining := time.Duration(entry.TotalDelaySecs)*time.Second - elapsedDuration
-
-			if remaining > 0 {
-				link := fmt.Sprintf("/status/%d", entry.ID)
-				content += fmt.Sprintf(
-					"<p><a href=\"%s\"><strong>%d - %s</strong></a> - in-progress, remaining time %.0f seconds</p>",
-					link, entry.ID, entry.Name, remaining.Seconds(),
-				)
-			}
+	for _, entry := range entries {
+		if entry.IsCompleted || entry.IsCancelled {
+			continue
+		}
+
+		elapsedSecs, currentStatus, _ := getStatusDetails(entry)
+		remainingSecs := entry.TotalDelaySecs - elapsedSecs
+		if remainingSecs < 0 {
+			remainingSecs = 0
 		}
+
+		activeCount++
+		link := fmt.Sprintf("/status/%d", entry.ID)
+		content += fmt.Sprintf(
+			"<p><a href=\"%s\"><strong>%d - %s</strong></a> - %s, remaining time %d seconds</p>",
+			link, entry.ID, entry.Name, currentStatus, remainingSecs,
+		)
 	}
 
-	if activeCount == 0 && len(history) > 0 {
+	if activeCount == 0 && len(entries) > 0 {
 		content += "<p>All queued tasks are completed.</p>"
-	} else if len(history) == 0 {
+	} else if len(entries) == 0 {
 		content += "<p>No countdowns activated.</p>"
 	}
 
@@ -308,16 +398,17 @@ func statusIndexHandler(w http.ResponseWriter, r *http.Request) {
 func statusDetailHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
-	jobID, err := parseJobIDFromPath(r.URL.Path, "status")
+	jobID, err := idVar(r)
 	if err != nil {
 		http.Error(w, "Invalid request URL format. Use /status/<ID>", http.StatusBadRequest)
 		return
 	}
 
-	mu.Lock()
-	entry, ok := history[jobID]
-	mu.Unlock()
-
+	entry, ok, err := store.Get(jobID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	if !ok {
 		http.Error(w, fmt.Sprintf("Job ID %d not found.", jobID), http.StatusNotFound)
 		return
@@ -331,7 +422,7 @@ func statusDetailHandler(w http.ResponseWriter, r *http.Request) {
 
 	content := fmt.Sprintf("<h1>Status for Job ID: %d (%s)</h1>", jobID, entry.Name)
 	content += fmt.Sprintf("<p>Status: <strong>%s</strong></p>", currentStatus)
-	if currentStatus == "in-progress" {
+	if currentStatus == "in-progress" || currentStatus == "paused" {
 		content += fmt.Sprintf("<p>Remaining Time: %d seconds</p>", remainingSecs)
 	}
 	content += fmt.Sprintf("<p>Total Delay Requested: %d seconds</p>", entry.TotalDelaySecs)
@@ -340,28 +431,105 @@ func statusDetailHandler(w http.ResponseWriter, r *http.Request) {
 	if entry.CompletedTime != nil {
 		content += fmt.Sprintf("<p>Completed Time: %s</p>", entry.CompletedTime.Format(timeFormat))
 	}
+	if entry.CancelledTime != nil {
+		content += fmt.Sprintf("<p>Cancelled Time: %s</p>", entry.CancelledTime.Format(timeFormat))
+	}
+
+	content += statusActionFormsHTML(jobID, currentStatus)
 
 	writeResponseWithNav(w, content)
 }
 
-// --- API Handlers ---
+// statusActionFormsHTML renders the pause/resume/cancel/extend controls
+// available for a job in its current status.
+func statusActionFormsHTML(jobID int, currentStatus string) string {
+	if currentStatus != "in-progress" && currentStatus != "paused" {
+		return ""
+	}
 
-func apiStatusIndexHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	content := `<div class="form-group">`
+	switch currentStatus {
+	case "in-progress":
+		content += fmt.Sprintf(`<form method="POST" action="/status/%d/pause" style="display:inline">
+			<button type="submit">Pause</button></form>`, jobID)
+	case "paused":
+		content += fmt.Sprintf(`<form method="POST" action="/status/%d/resume" style="display:inline">
+			<button type="submit">Resume</button></form>`, jobID)
+	}
+	content += fmt.Sprintf(`<form method="POST" action="/status/%d/cancel" style="display:inline">
+		<button type="submit">Cancel</button></form>`, jobID)
+	content += `</div>`
+
+	content += fmt.Sprintf(`<form method="POST" action="/status/%d/extend">
+		<div class="form-group">
+			<label for="addSeconds">Extend By (secs):</label>
+			<input type="number" id="addSeconds" name="addSeconds" required min="1">
+		</div>
+		<button type="submit">Extend</button>
+	</form>`, jobID)
+
+	return content
+}
 
-	mu.Lock()
-	defer mu.Unlock()
+// statusActionHandler serves the HTML-form equivalents of the job-control
+// API: POST /status/{id}/pause, /resume, /cancel, /extend.
+func statusActionHandler(w http.ResponseWriter, r *http.Request) {
+	jobID, err := idVar(r)
+	if err != nil {
+		http.Error(w, "Invalid request URL format.", http.StatusBadRequest)
+		return
+	}
+	action := mux.Vars(r)["action"]
+
+	var actionErr error
+	switch action {
+	case "pause":
+		actionErr = pauseJob(jobID)
+	case "resume":
+		actionErr = resumeJob(jobID)
+	case "cancel":
+		actionErr = cancelJob(jobID)
+	case "extend":
+		addSeconds, convErr := strconv.Atoi(r.FormValue("addSeconds"))
+		if convErr != nil {
+			http.Error(w, "Invalid addSeconds value", http.StatusBadRequest)
+			return
+		}
+		actionErr = extendJob(jobID, addSeconds)
+	default:
+		http.Error(w, fmt.Sprintf("Unknown action %q.", action), http.StatusNotFound)
+		return
+	}
+
+	if actionErr != nil {
+		http.Error(w, actionErr.Error(), jobActionHTTPStatus(actionErr))
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/status/%d", jobID), http.StatusSeeOther)
+}
+
+// --- API Handlers ---
+
+// buildApiStatusList snapshots the current history into API response objects.
+// It is shared by apiStatusIndexHandler and the status hub's SSE feeds.
+func buildApiStatusList() []ApiStatusResponse {
+	entries, err := store.All()
+	if err != nil {
+		log.Printf("failed to snapshot job store: %v", err)
+		return []ApiStatusResponse{}
+	}
 
 	responseList := []ApiStatusResponse{}
 
-	for _, entry := range history {
+	for _, entry := range entries {
 		elapsedSecs, currentStatus, _ := getStatusDetails(entry)
 		remainingSecs := entry.TotalDelaySecs - elapsedSecs
 		if remainingSecs < 0 {
 			remainingSecs = 0
 		}
 
-		apiResponse := ApiStatusResponse{
+		responseList = append(responseList, ApiStatusResponse{
 			ID:                entry.ID,
 			Name:              entry.Name,
 			DateTimeAdded:     entry.DateTimeAdded,
@@ -370,30 +538,120 @@ func apiStatusIndexHandler(w http.ResponseWriter, r *http.Request) {
 			ElapsedTimeSecs:   elapsedSecs,
 			RemainingTimeSecs: remainingSecs,
 			CompletedTime:     entry.CompletedTime,
-		}
-		responseList = append(responseList, apiResponse)
+			CancelledTime:     entry.CancelledTime,
+
+			CallbackURL:           entry.CallbackURL,
+			WebhookAttempts:       entry.WebhookAttempts,
+			WebhookLastStatusCode: entry.WebhookLastStatusCode,
+			WebhookDelivered:      entry.WebhookDelivered,
+		})
 	}
 
-	if err := json.NewEncoder(w).Encode(responseList); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	return responseList
+}
+
+// startRequest is the JSON body for POST /api/start.
+type startRequest struct {
+	Name        string `json:"name"`
+	DelaySecs   int    `json:"delaySeconds"`
+	CallbackURL string `json:"callbackUrl,omitempty"`
+}
+
+// apiStartHandler serves POST /api/start, the JSON equivalent of the
+// "/start" HTML form.
+func apiStartHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	var req startRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorJSON(w, http.StatusBadRequest, "invalid_request",
+			`Invalid JSON body. Expected {"name":"...","delaySeconds":N,"callbackUrl":"..."}`)
+		return
+	}
+	if req.DelaySecs < 1 {
+		writeErrorJSON(w, http.StatusBadRequest, "invalid_request", "delaySeconds must be at least 1")
+		return
+	}
+
+	newEntry, err := createJob(req.Name, req.DelaySecs, req.CallbackURL)
+	if err != nil {
+		writeErrorJSON(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(newEntry); err != nil {
+		writeErrorJSON(w, http.StatusInternalServerError, "internal_error", err.Error())
 	}
 }
 
-func apiStatusDetailHandler(w http.ResponseWriter, r *http.Request) {
+// apiCountdownsHandler serves POST /api/countdowns, the REST-conventional
+// counterpart of apiStartHandler: it reports the created entry with a 201
+// and a Location header pointing at its status resource.
+func apiCountdownsHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 
-	jobID, err := parseAPIJobIDFromPath(r.URL.Path)
+	var req startRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorJSON(w, http.StatusBadRequest, "invalid_request",
+			`Invalid JSON body. Expected {"name":"...","delaySeconds":N,"callbackUrl":"..."}`)
+		return
+	}
+	if req.DelaySecs < 1 {
+		writeErrorJSON(w, http.StatusBadRequest, "invalid_request", "delaySeconds must be at least 1")
+		return
+	}
+
+	newEntry, err := createJob(req.Name, req.DelaySecs, req.CallbackURL)
 	if err != nil {
-		http.Error(w, "Invalid request URL format. Use /api/status/<ID>", http.StatusBadRequest)
+		writeErrorJSON(w, http.StatusInternalServerError, "internal_error", err.Error())
 		return
 	}
 
-	mu.Lock()
-	entry, ok := history[jobID]
-	mu.Unlock()
+	w.Header().Set("Location", fmt.Sprintf("/api/status/%d", newEntry.ID))
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(newEntry); err != nil {
+		log.Printf("failed to encode created job response: %v", err)
+	}
+}
 
+// apiStatusIndexHandler serves GET /api/status, with optional filtering,
+// sorting, and pagination via ?status=&limit=&offset=&sort=.
+func apiStatusIndexHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	params, err := parseStatusListParams(r.URL.Query())
+	if err != nil {
+		writeErrorJSON(w, http.StatusBadRequest, "invalid_query", err.Error())
+		return
+	}
+
+	list, err := params.apply(buildApiStatusList())
+	if err != nil {
+		writeErrorJSON(w, http.StatusBadRequest, "invalid_query", err.Error())
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(list); err != nil {
+		writeErrorJSON(w, http.StatusInternalServerError, "internal_error", err.Error())
+	}
+}
+
+func apiStatusDetailHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	jobID, err := idVar(r)
+	if err != nil {
+		writeErrorJSON(w, http.StatusBadRequest, "invalid_request", "Invalid request URL format. Use /api/status/<ID>")
+		return
+	}
+
+	entry, ok, err := store.Get(jobID)
+	if err != nil {
+		writeErrorJSON(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return
+	}
 	if !ok {
-		http.Error(w, fmt.Sprintf("Job ID %d not found.", jobID), http.StatusNotFound)
+		writeErrorJSON(w, http.StatusNotFound, "job_not_found", fmt.Sprintf("Job ID %d not found.", jobID))
 		return
 	}
 
@@ -412,55 +670,211 @@ func apiStatusDetailHandler(w http.ResponseWriter, r *http.Request) {
 		ElapsedTimeSecs:   elapsedSecs,
 		RemainingTimeSecs: remainingSecs,
 		CompletedTime:     entry.CompletedTime,
+		CancelledTime:     entry.CancelledTime,
+
+		CallbackURL:           entry.CallbackURL,
+		WebhookAttempts:       entry.WebhookAttempts,
+		WebhookLastStatusCode: entry.WebhookLastStatusCode,
+		WebhookDelivered:      entry.WebhookDelivered,
 	}
 
 	if err := json.NewEncoder(w).Encode(apiResponse); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeErrorJSON(w, http.StatusInternalServerError, "internal_error", err.Error())
+	}
+}
+
+// apiStatusCancelHandler serves DELETE /api/status/{id}.
+func apiStatusCancelHandler(w http.ResponseWriter, r *http.Request) {
+	jobID, err := idVar(r)
+	if err != nil {
+		writeErrorJSON(w, http.StatusBadRequest, "invalid_request", "Invalid request URL format. Use /api/status/<ID>")
+		return
+	}
+	if err := cancelJob(jobID); err != nil {
+		writeErrorJSON(w, jobActionHTTPStatus(err), jobActionErrorCode(err), err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// apiStatusPauseHandler serves POST /api/status/{id}/pause.
+func apiStatusPauseHandler(w http.ResponseWriter, r *http.Request) {
+	jobID, err := idVar(r)
+	if err != nil {
+		writeErrorJSON(w, http.StatusBadRequest, "invalid_request", "Invalid request URL format. Use /api/status/<ID>/pause")
+		return
+	}
+	if err := pauseJob(jobID); err != nil {
+		writeErrorJSON(w, jobActionHTTPStatus(err), jobActionErrorCode(err), err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// apiStatusResumeHandler serves POST /api/status/{id}/resume.
+func apiStatusResumeHandler(w http.ResponseWriter, r *http.Request) {
+	jobID, err := idVar(r)
+	if err != nil {
+		writeErrorJSON(w, http.StatusBadRequest, "invalid_request", "Invalid request URL format. Use /api/status/<ID>/resume")
+		return
 	}
+	if err := resumeJob(jobID); err != nil {
+		writeErrorJSON(w, jobActionHTTPStatus(err), jobActionErrorCode(err), err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// extendRequest is the JSON body for POST /api/status/{id}/extend.
+type extendRequest struct {
+	AddSeconds int `json:"addSeconds"`
+}
+
+// apiStatusExtendHandler serves POST /api/status/{id}/extend.
+func apiStatusExtendHandler(w http.ResponseWriter, r *http.Request) {
+	jobID, err := idVar(r)
+	if err != nil {
+		writeErrorJSON(w, http.StatusBadRequest, "invalid_request", "Invalid request URL format. Use /api/status/<ID>/extend")
+		return
+	}
+
+	var req extendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorJSON(w, http.StatusBadRequest, "invalid_request", `Invalid JSON body. Expected {"addSeconds": N}`)
+		return
+	}
+
+	if err := extendJob(jobID, req.AddSeconds); err != nil {
+		writeErrorJSON(w, jobActionHTTPStatus(err), jobActionErrorCode(err), err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
 }
 
 // --- runTimer Function & Main execution ---
 
-func runTimer(jobID int, delay int) {
-	time.Sleep(time.Duration(delay) * time.Second)
+// resumePendingJobs reloads persisted entries on startup and re-schedules
+// runTimer for anything still in progress or paused; runTimer itself derives
+// the correct remaining time (and paused state) from the store. Anything
+// whose deadline has already passed is completed immediately instead. It
+// also fast-forwards the ID/sequence counters past whatever was persisted.
+func resumePendingJobs() {
+	entries, err := store.All()
+	if err != nil {
+		log.Fatalf("failed to load persisted jobs: %v", err)
+	}
 
-	mu.Lock()
-	defer mu.Unlock()
+	maxID, maxSeq := 0, int64(0)
+	for _, entry := range entries {
+		if entry.ID > maxID {
+			maxID = entry.ID
+		}
+		if entry.Seq > maxSeq {
+			maxSeq = entry.Seq
+		}
 
-	if entry, ok := history[jobID]; ok {
-		entry.IsCompleted = true
-		now := time.Now()
-		entry.CompletedTime = &now
-		history[jobID] = entry
-		log.Printf("Timer Job ID %d completed at %s.\n", jobID, now.Format(timeFormat))
+		if entry.IsCompleted || entry.IsCancelled {
+			continue
+		}
+
+		if remainingFor(entry.ID) <= 0 && entry.PausedAt == nil {
+			completeJob(entry.ID)
+			continue
+		}
+
+		log.Printf("Resuming Job ID %d, %s remaining.\n", entry.ID, remainingFor(entry.ID))
+		go runTimer(entry.ID)
 	}
+
+	idMu.Lock()
+	nextEntryID, nextSeq = maxID+1, maxSeq+1
+	idMu.Unlock()
 }
 
-func main() {
-	http.HandleFunc("/", indexHandler)
-	http.HandleFunc("/start", startHandler)
+// envOrDefault returns the value of the named environment variable, or def
+// if it isn't set. Flags of the same name take precedence over this.
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
 
-	http.HandleFunc("/status/", func(w http.ResponseWriter, r *http.Request) {
-		path := strings.TrimSuffix(r.URL.Path, "/")
-		if path == "/status" {
-			statusIndexHandler(w, r)
-		} else {
-			statusDetailHandler(w, r)
+// envIntOrDefault is envOrDefault for integer-valued settings.
+func envIntOrDefault(key string, def int) int {
+	v, err := strconv.Atoi(envOrDefault(key, strconv.Itoa(def)))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func main() {
+	storeBackend := flag.String("store", envOrDefault("COUNTDOWN_STORE", "bolt"), `job store backend: "bolt" or "memory"`)
+	storePath := flag.String("store-path", envOrDefault("COUNTDOWN_STORE_PATH", "countdown.db"), "path to the bolt store file")
+	maxHistoryFlag := flag.Int("max-history", envIntOrDefault("COUNTDOWN_MAX_HISTORY", maxHistory), "maximum number of countdown jobs retained")
+	flag.Parse()
+
+	maxHistory = *maxHistoryFlag
+
+	var err error
+	switch *storeBackend {
+	case "memory":
+		store = newMemoryStore(maxHistory)
+	case "bolt":
+		store, err = newBoltStore(*storePath, maxHistory)
+		if err != nil {
+			log.Fatalf("failed to open store: %v", err)
 		}
+	default:
+		log.Fatalf("unknown store backend %q (want \"bolt\" or \"memory\")", *storeBackend)
+	}
+	defer store.Close()
+
+	startWebhookWorkers(envIntOrDefault("COUNTDOWN_WEBHOOK_WORKERS", 4))
+
+	resumePendingJobs()
+
+	router := mux.NewRouter()
+	router.StrictSlash(true)
+	router.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeErrorJSON(w, http.StatusMethodNotAllowed, "method_not_allowed",
+			fmt.Sprintf("Method %s not allowed for %s", r.Method, r.URL.Path))
 	})
 
-	http.HandleFunc("/api/status/", func(w http.ResponseWriter, r *http.Request) {
-		path := strings.TrimSuffix(r.URL.Path, "/")
-		if path == "/api/status" {
-			apiStatusIndexHandler(w, r)
-		} else {
-			apiStatusDetailHandler(w, r)
+	router.HandleFunc("/", indexHandler)
+	router.HandleFunc("/start", startHandler)
+	router.HandleFunc("/api/start", apiStartHandler).Methods(http.MethodPost)
+	router.HandleFunc("/api/countdowns", apiCountdownsHandler).Methods(http.MethodPost)
+
+	router.HandleFunc("/status", statusIndexHandler)
+	router.HandleFunc("/status/{id:[0-9]+}", statusDetailHandler)
+	router.HandleFunc("/status/{id:[0-9]+}/{action}", statusActionHandler).Methods(http.MethodPost)
+
+	router.HandleFunc("/api/status", apiStatusIndexHandler)
+	router.HandleFunc("/api/status/stream", apiStatusStreamHandler)
+	router.HandleFunc("/api/status/{id:[0-9]+}", apiStatusDetailHandler).Methods(http.MethodGet)
+	router.HandleFunc("/api/status/{id:[0-9]+}", apiStatusCancelHandler).Methods(http.MethodDelete)
+	router.HandleFunc("/api/status/{id:[0-9]+}/stream", apiStatusDetailStreamHandler)
+	router.HandleFunc("/api/status/{id:[0-9]+}/pause", apiStatusPauseHandler).Methods(http.MethodPost)
+	router.HandleFunc("/api/status/{id:[0-9]+}/resume", apiStatusResumeHandler).Methods(http.MethodPost)
+	router.HandleFunc("/api/status/{id:[0-9]+}/extend", apiStatusExtendHandler).Methods(http.MethodPost)
+
+	router.Handle("/metrics", metricsHandler())
+
+	http.Handle("/", router)
+
+	go func() {
+		ticker := time.NewTicker(1 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			refreshJobMetrics()
+			statusBroadcast.publish(statusEvent{Jobs: buildApiStatusList()})
 		}
-	})
+	}()
 
 	fmt.Println("Server starting on http://localhost:8080")
 	if err := http.ListenAndServe(":8080", nil); err != nil {
 		log.Fatal(err)
 	}
 }
-
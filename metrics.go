@@ -0,0 +1,89 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	jobsTotal = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "countdown_jobs_total",
+		Help: "Current number of countdown jobs, by status.",
+	}, []string{"status"})
+
+	jobRemainingSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "countdown_job_remaining_seconds",
+		Help: "Remaining time in seconds for an in-progress countdown job.",
+	}, []string{"id", "name"})
+
+	jobTotalSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "countdown_job_total_seconds",
+		Help: "Total delay requested for a countdown job, in seconds.",
+	}, []string{"id", "name"})
+
+	jobCompletionDriftSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "countdown_job_completion_drift_seconds",
+		Help:    "Wall-clock completion time minus expected completion time, in seconds.",
+		Buckets: prometheus.DefBuckets,
+	})
+)
+
+func init() {
+	prometheus.MustRegister(jobsTotal, jobRemainingSeconds, jobTotalSeconds, jobCompletionDriftSeconds)
+}
+
+// jobStatusLabels are every status value a job can report, in its
+// countdown_jobs_total label form (underscores, matching Prometheus
+// convention, vs. the hyphenated strings getStatusDetails returns).
+var jobStatusLabels = []string{"completed", "in_progress", "paused", "cancelled"}
+
+// metricsStatusLabel maps a getStatusDetails status ("in-progress") to its
+// countdown_jobs_total label value ("in_progress").
+func metricsStatusLabel(status string) string {
+	return strings.ReplaceAll(status, "-", "_")
+}
+
+// refreshJobMetrics recomputes the gauges from the current store snapshot.
+func refreshJobMetrics() {
+	entries, err := store.All()
+	if err != nil {
+		log.Printf("failed to refresh job metrics: %v", err)
+		return
+	}
+
+	jobRemainingSeconds.Reset()
+	jobTotalSeconds.Reset()
+
+	counts := make(map[string]int, len(jobStatusLabels))
+	for _, entry := range entries {
+		elapsedSecs, currentStatus, _ := getStatusDetails(entry)
+		labels := prometheus.Labels{"id": strconv.Itoa(entry.ID), "name": entry.Name}
+		jobTotalSeconds.With(labels).Set(float64(entry.TotalDelaySecs))
+
+		counts[metricsStatusLabel(currentStatus)]++
+
+		if currentStatus == "completed" || currentStatus == "cancelled" {
+			continue
+		}
+
+		remainingSecs := entry.TotalDelaySecs - elapsedSecs
+		if remainingSecs < 0 {
+			remainingSecs = 0
+		}
+		jobRemainingSeconds.With(labels).Set(float64(remainingSecs))
+	}
+
+	for _, status := range jobStatusLabels {
+		jobsTotal.WithLabelValues(status).Set(float64(counts[status]))
+	}
+}
+
+// metricsHandler serves the Prometheus text-format exposition.
+func metricsHandler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// writeStatusEventJSON marshals v as a single SSE "data:" event and flushes it.
+func writeStatusEventJSON(w http.ResponseWriter, flusher http.Flusher, v interface{}) bool {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+	fmt.Fprintf(w, "data: %s\n\n", data)
+	flusher.Flush()
+	return true
+}
+
+// apiStatusStreamHandler serves /api/status/stream, pushing a snapshot of
+// every job whenever the status hub publishes one (roughly once per second,
+// plus immediately on completion).
+func apiStatusStreamHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch := statusBroadcast.subscribe()
+	defer statusBroadcast.unsubscribe(ch)
+
+	writeStatusEventJSON(w, flusher, buildApiStatusList())
+
+	for {
+		select {
+		case ev := <-ch:
+			writeStatusEventJSON(w, flusher, ev.Jobs)
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// apiStatusDetailStreamHandler serves /api/status/{id}/stream, pushing only
+// the entry matching jobID out of each hub snapshot.
+func apiStatusDetailStreamHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	jobID, err := idVar(r)
+	if err != nil {
+		http.Error(w, "Invalid request URL format. Use /api/status/<ID>/stream", http.StatusBadRequest)
+		return
+	}
+
+	_, exists, err := store.Get(jobID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		http.Error(w, fmt.Sprintf("Job ID %d not found.", jobID), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch := statusBroadcast.subscribe()
+	defer statusBroadcast.unsubscribe(ch)
+
+	sendJob := func(jobs []ApiStatusResponse) {
+		for _, job := range jobs {
+			if job.ID == jobID {
+				writeStatusEventJSON(w, flusher, job)
+				return
+			}
+		}
+	}
+
+	sendJob(buildApiStatusList())
+
+	for {
+		select {
+		case ev := <-ch:
+			sendJob(ev.Jobs)
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// statusListParams holds the GET /api/status query parameters controlling
+// filtering, ordering, and pagination:
+// ?status=in_progress&limit=50&offset=0&sort=-dateTimeAdded.
+type statusListParams struct {
+	status string
+	sort   string
+	limit  int
+	offset int
+}
+
+// parseStatusListParams reads statusListParams out of a request's query
+// values. status accepts either hyphen or underscore word separators
+// ("in-progress" or "in_progress") since the status strings themselves use
+// hyphens but query params conventionally use underscores.
+func parseStatusListParams(q url.Values) (statusListParams, error) {
+	p := statusListParams{
+		status: strings.ReplaceAll(q.Get("status"), "_", "-"),
+		sort:   q.Get("sort"),
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			return p, fmt.Errorf("limit must be a non-negative integer")
+		}
+		p.limit = limit
+	}
+
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return p, fmt.Errorf("offset must be a non-negative integer")
+		}
+		p.offset = offset
+	}
+
+	return p, nil
+}
+
+// statusSortFields maps a sort query value to a less-than comparator;
+// prefixing the value with "-" reverses it.
+var statusSortFields = map[string]func(a, b ApiStatusResponse) bool{
+	"dateTimeAdded": func(a, b ApiStatusResponse) bool { return a.DateTimeAdded.Before(b.DateTimeAdded) },
+	"id":            func(a, b ApiStatusResponse) bool { return a.ID < b.ID },
+}
+
+// apply filters list by status, sorts it, and slices out the requested
+// page, in that order.
+func (p statusListParams) apply(list []ApiStatusResponse) ([]ApiStatusResponse, error) {
+	if p.status != "" {
+		filtered := make([]ApiStatusResponse, 0, len(list))
+		for _, entry := range list {
+			if entry.Status == p.status {
+				filtered = append(filtered, entry)
+			}
+		}
+		list = filtered
+	}
+
+	if p.sort != "" {
+		field := strings.TrimPrefix(p.sort, "-")
+		descending := strings.HasPrefix(p.sort, "-")
+
+		less, ok := statusSortFields[field]
+		if !ok {
+			return nil, fmt.Errorf("unsupported sort field %q", field)
+		}
+		sort.SliceStable(list, func(i, j int) bool {
+			if descending {
+				return less(list[j], list[i])
+			}
+			return less(list[i], list[j])
+		})
+	}
+
+	if p.offset > 0 {
+		if p.offset >= len(list) {
+			return []ApiStatusResponse{}, nil
+		}
+		list = list[p.offset:]
+	}
+	if p.limit > 0 && p.limit < len(list) {
+		list = list[:p.limit]
+	}
+
+	return list, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"sort"
+	"sync"
+)
+
+// Store persists DelayEntry records and enforces the configured history
+// bound. Implementations must be safe for concurrent use.
+type Store interface {
+	// Save upserts entry under entry.ID.
+	Save(entry DelayEntry) error
+	// Get retrieves a single entry by ID.
+	Get(id int) (DelayEntry, bool, error)
+	// All returns every stored entry, ordered by creation sequence.
+	All() ([]DelayEntry, error)
+	// Delete removes an entry. It is a no-op if the entry doesn't exist.
+	Delete(id int) error
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+// sortBySeq orders entries by their monotonic creation sequence, matching
+// insertion order even after a restart.
+func sortBySeq(entries []DelayEntry) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Seq < entries[j].Seq })
+}
+
+// memoryStore is the original, non-persistent Store backed by a plain map.
+// History is bounded to limit by deleting the oldest entries on each Save.
+type memoryStore struct {
+	mu      sync.Mutex
+	entries map[int]DelayEntry
+	limit   int
+}
+
+func newMemoryStore(limit int) *memoryStore {
+	return &memoryStore{entries: make(map[int]DelayEntry), limit: limit}
+}
+
+func (s *memoryStore) Save(entry DelayEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[entry.ID] = entry
+	s.pruneLocked()
+	return nil
+}
+
+func (s *memoryStore) Get(id int) (DelayEntry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[id]
+	return entry, ok, nil
+}
+
+func (s *memoryStore) All() ([]DelayEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all := make([]DelayEntry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		all = append(all, entry)
+	}
+	sortBySeq(all)
+	return all, nil
+}
+
+func (s *memoryStore) Delete(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, id)
+	return nil
+}
+
+func (s *memoryStore) Close() error { return nil }
+
+// pruneLocked deletes the oldest *completed or cancelled* entries until the
+// history bound is met, evicting in creation order. In-progress and paused
+// jobs are never evicted: their runTimer goroutine, pause/cancel/extend
+// controls, and any callback all depend on the entry still being in the
+// store, so silently dropping it would orphan all of that. If there aren't
+// enough terminal entries to reach the bound, the store is simply left over
+// limit until some do finish or get cancelled. Callers must hold s.mu.
+func (s *memoryStore) pruneLocked() {
+	if s.limit <= 0 || len(s.entries) <= s.limit {
+		return
+	}
+
+	all := make([]DelayEntry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		all = append(all, entry)
+	}
+	sortBySeq(all)
+
+	excess := len(all) - s.limit
+	for _, entry := range all {
+		if excess <= 0 {
+			break
+		}
+		if !entry.IsCompleted && !entry.IsCancelled {
+			continue
+		}
+		delete(s.entries, entry.ID)
+		excess--
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+)
+
+// webhookPayload is POSTed to a job's callback URL on completion.
+type webhookPayload struct {
+	ID                int       `json:"id"`
+	Name              string    `json:"name"`
+	TotalDelaySeconds int       `json:"totalDelaySeconds"`
+	CompletedTime     time.Time `json:"completedTime"`
+	Status            string    `json:"status"`
+}
+
+// webhookRetryDelays are the waits before each retry once the initial
+// delivery attempt fails, in order.
+var webhookRetryDelays = []time.Duration{
+	1 * time.Second,
+	5 * time.Second,
+	30 * time.Second,
+	2 * time.Minute,
+	10 * time.Minute,
+}
+
+const webhookSignatureHeader = "X-Countdown-Signature"
+
+// webhookSecret signs outgoing callback bodies so receivers can verify they
+// came from this server. An empty secret still signs (with an empty key)
+// rather than skip the header, keeping the wire format uniform.
+var webhookSecret = []byte(envOrDefault("COUNTDOWN_WEBHOOK_SECRET", ""))
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// webhookJob is one callback delivery attempt queued onto the worker pool.
+// attempt is 1 on the first try and increments each time a failed delivery
+// is rescheduled.
+type webhookJob struct {
+	jobID   int
+	url     string
+	payload webhookPayload
+	attempt int
+}
+
+// webhookQueue feeds the bounded pool of delivery workers started by
+// startWebhookWorkers, so a flood of simultaneous completions can't spawn
+// unbounded goroutines.
+var webhookQueue chan webhookJob
+
+// startWebhookWorkers launches n goroutines draining webhookQueue.
+func startWebhookWorkers(n int) {
+	webhookQueue = make(chan webhookJob, 256)
+	for i := 0; i < n; i++ {
+		go webhookWorker()
+	}
+}
+
+func webhookWorker() {
+	for job := range webhookQueue {
+		deliverWebhook(job)
+	}
+}
+
+// enqueueWebhook schedules a completion callback for delivery. If the
+// queue is full, the job is dropped and logged rather than blocking the
+// caller (completeJob, running inline in a runTimer goroutine).
+func enqueueWebhook(job webhookJob) {
+	if job.attempt == 0 {
+		job.attempt = 1
+	}
+	select {
+	case webhookQueue <- job:
+	default:
+		log.Printf("webhook queue full, dropping callback for Job ID %d", job.jobID)
+	}
+}
+
+// deliverWebhook makes a single delivery attempt for job.payload. On
+// failure it doesn't retry inline: a failing endpoint would otherwise tie
+// up one of the worker pool's goroutines for the whole backoff window, and
+// a handful of simultaneously-failing endpoints could starve delivery for
+// brand-new, healthy completions. Instead it schedules the next attempt
+// with time.AfterFunc, which re-enqueues the job onto webhookQueue once its
+// backoff elapses, so a retry occupies no worker until it's actually ready
+// to run.
+func deliverWebhook(job webhookJob) {
+	body, err := json.Marshal(job.payload)
+	if err != nil {
+		log.Printf("failed to marshal webhook payload for Job ID %d: %v", job.jobID, err)
+		return
+	}
+	signature := signWebhookBody(body)
+
+	statusCode, err := postWebhook(job.url, body, signature)
+	recordWebhookAttempt(job.jobID, job.attempt, statusCode)
+
+	if err == nil && statusCode >= 200 && statusCode < 300 {
+		recordWebhookDelivered(job.jobID)
+		return
+	}
+	log.Printf("webhook delivery attempt %d for Job ID %d failed: status=%d err=%v",
+		job.attempt, job.jobID, statusCode, err)
+
+	if job.attempt > len(webhookRetryDelays) {
+		log.Printf("webhook delivery for Job ID %d exhausted all retries", job.jobID)
+		return
+	}
+
+	retry := job
+	retry.attempt++
+	time.AfterFunc(webhookRetryDelays[job.attempt-1], func() { enqueueWebhook(retry) })
+}
+
+func postWebhook(url string, body []byte, signature string) (statusCode int, err error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookSignatureHeader, signature)
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func signWebhookBody(body []byte) string {
+	mac := hmac.New(sha256.New, webhookSecret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func recordWebhookAttempt(jobID, attempts, statusCode int) {
+	unlock := lockEntry(jobID)
+	defer unlock()
+
+	entry, ok, err := store.Get(jobID)
+	if err != nil || !ok {
+		return
+	}
+	entry.WebhookAttempts = attempts
+	entry.WebhookLastStatusCode = statusCode
+	if err := store.Save(entry); err != nil {
+		log.Printf("failed to persist webhook attempt for Job ID %d: %v", jobID, err)
+	}
+}
+
+func recordWebhookDelivered(jobID int) {
+	unlock := lockEntry(jobID)
+	defer unlock()
+
+	entry, ok, err := store.Get(jobID)
+	if err != nil || !ok {
+		return
+	}
+	entry.WebhookDelivered = true
+	if err := store.Save(entry); err != nil {
+		log.Printf("failed to persist webhook delivery for Job ID %d: %v", jobID, err)
+	}
+}